@@ -0,0 +1,34 @@
+package securestore
+
+// Config carries backend-specific settings that don't fit into the plain `backend` string
+// accepted by the rest of this package, such as where the file backend should keep its
+// encrypted store and how it should obtain its passphrase. It is accepted as an optional,
+// variadic argument by UpsertConfigToKeyring, GetConfigFromKeyring and RemoveConfigFromKeyring
+// so existing callers that only pass a backend name keep working unchanged.
+type Config struct {
+	// FileDir is the directory the file backend uses to persist its encrypted store. Defaults
+	// to DefaultFileDir when empty.
+	FileDir string
+
+	// FilePassphraseFunc supplies the passphrase used to encrypt and decrypt the file backend's
+	// store. Defaults to DefaultPassphraseFunc when nil.
+	FilePassphraseFunc PassphraseFunc
+
+	// OnePasswordVault is the vault the 1Password backend reads and writes items in. Defaults to
+	// DefaultOnePasswordVault when empty.
+	OnePasswordVault string
+
+	// OnePasswordAccount is the `op` account shorthand to operate against, equivalent to the
+	// CLI's `--account` flag. Left empty, `op` uses its signed-in default account.
+	OnePasswordAccount string
+}
+
+// firstConfig returns the first Config passed to a variadic `cfg ...Config` parameter, or the
+// zero value if none was given.
+func firstConfig(cfg ...Config) Config {
+	if len(cfg) == 0 {
+		return Config{}
+	}
+
+	return cfg[0]
+}
@@ -0,0 +1,76 @@
+package securestore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAccessDenied indicates that the user, or a non-interactive session, denied, cancelled, or
+// was otherwise refused access to the secure store. Callers can check for it with errors.Is
+// instead of parsing backend- and locale-specific error strings, and use it as a signal to
+// prompt the user to re-authenticate.
+var ErrAccessDenied = errors.New("access to the secure store was denied")
+
+// lockedSubstrings lists backend error text fragments that indicate the secure store is locked
+// and needs to be unlocked, as distinct from the user having denied access outright.
+var lockedSubstrings = []string{
+	"Keychain Error. (-25308)", // macOS errSecInteractionNotAllowed
+	"is locked",                // SecretService / KWallet collections
+	"prompt dismissed",         // SecretService polkit prompt dismissed
+}
+
+// deniedSubstrings lists backend error text fragments that indicate access was denied or
+// cancelled by the user.
+var deniedSubstrings = []string{
+	"Keychain Error. (-25244)",                // macOS errSecAuthFailed
+	"Keychain Error. (-128)",                  // macOS errSecUserCanceled
+	"org.freedesktop.DBus.Error.AccessDenied", // SecretService
+	"ERROR_ACCESS_DENIED",                     // WinCred
+	"Access is denied",                        // WinCred
+	"gpg: decryption failed",                  // pass
+	"failed to decrypt item",                  // file backend wrong passphrase
+	"not currently signed in",                 // op (1Password)
+}
+
+// wrapAccessError maps backend-specific denial and lock errors onto ErrAccessDenied, preserving
+// the original message so the underlying cause is still visible.
+func wrapAccessError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	message := err.Error()
+
+	for _, substr := range lockedSubstrings {
+		if strings.Contains(message, substr) {
+			return fmt.Errorf("%w: %v", ErrAccessDenied, err)
+		}
+	}
+
+	for _, substr := range deniedSubstrings {
+		if strings.Contains(message, substr) {
+			return fmt.Errorf("%w: %v", ErrAccessDenied, err)
+		}
+	}
+
+	return err
+}
+
+// IsLocked reports whether err indicates that the secure store is locked and needs to be
+// unlocked, as opposed to the user having denied or cancelled access outright. CLI tools can use
+// this to decide whether to prompt for an unlock versus a full re-authentication.
+func IsLocked(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := err.Error()
+	for _, substr := range lockedSubstrings {
+		if strings.Contains(message, substr) {
+			return true
+		}
+	}
+
+	return false
+}
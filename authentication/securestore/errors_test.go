@@ -0,0 +1,63 @@
+package securestore
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWrapAccessErrorNil(t *testing.T) {
+	if err := wrapAccessError(nil); err != nil {
+		t.Fatalf("got %v, want nil", err)
+	}
+}
+
+func TestWrapAccessErrorMatches(t *testing.T) {
+	for _, message := range append(append([]string{}, lockedSubstrings...), deniedSubstrings...) {
+		original := errors.New("backend failure: " + message)
+
+		wrapped := wrapAccessError(original)
+		if !errors.Is(wrapped, ErrAccessDenied) {
+			t.Fatalf("message %q: errors.Is(wrapped, ErrAccessDenied) is false", message)
+		}
+
+		if !strings.Contains(wrapped.Error(), original.Error()) {
+			t.Fatalf("message %q: wrapped error lost the original message: %v", message, wrapped)
+		}
+	}
+}
+
+func TestWrapAccessErrorNoMatch(t *testing.T) {
+	original := errors.New("some unrelated failure")
+
+	wrapped := wrapAccessError(original)
+	if wrapped != original {
+		t.Fatalf("got %v, want the original error unwrapped", wrapped)
+	}
+
+	if errors.Is(wrapped, ErrAccessDenied) {
+		t.Fatal("got errors.Is true for an unrelated error")
+	}
+}
+
+func TestIsLocked(t *testing.T) {
+	for _, message := range lockedSubstrings {
+		if !IsLocked(errors.New("backend failure: " + message)) {
+			t.Fatalf("message %q: IsLocked returned false", message)
+		}
+	}
+
+	for _, message := range deniedSubstrings {
+		if IsLocked(errors.New("backend failure: " + message)) {
+			t.Fatalf("message %q: IsLocked returned true for a denied (non-locked) error", message)
+		}
+	}
+
+	if IsLocked(nil) {
+		t.Fatal("IsLocked(nil) returned true")
+	}
+
+	if IsLocked(errors.New("unrelated failure")) {
+		t.Fatal("IsLocked returned true for an unrelated error")
+	}
+}
@@ -0,0 +1,227 @@
+package securestore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/99designs/keyring"
+	jose "github.com/go-jose/go-jose/v4"
+	"golang.org/x/term"
+)
+
+const (
+	// FileBackend persists items as JWE-encrypted entries in a JSON file on disk. Unlike the
+	// other backends it never depends on an OS keyring service being reachable, which makes it
+	// the only backend that works unattended on headless Linux hosts and inside containers.
+	FileBackend = "file"
+
+	// DefaultFileDir is used as the file backend's store directory when Config.FileDir is empty.
+	DefaultFileDir = "~/.config/ocm/keyring"
+
+	// FilePassphraseEnvVar is read by DefaultPassphraseFunc before it falls back to an
+	// interactive terminal prompt.
+	FilePassphraseEnvVar = "OCM_KEYRING_FILE_PASSPHRASE"
+
+	fileStoreName = "keyring.json"
+)
+
+// PassphraseFunc returns the passphrase used to encrypt and decrypt the file backend's store. It
+// is invoked every time an item is encrypted or decrypted, so implementations are free to cache,
+// prompt or read from any source they like.
+type PassphraseFunc func() ([]byte, error)
+
+// DefaultPassphraseFunc reads the passphrase from the OCM_KEYRING_FILE_PASSPHRASE environment
+// variable and, when that isn't set, prompts for it on the terminal.
+func DefaultPassphraseFunc() ([]byte, error) {
+	if value, ok := os.LookupEnv(FilePassphraseEnvVar); ok {
+		return []byte(value), nil
+	}
+
+	fmt.Fprint(os.Stderr, "Enter passphrase for the OCM file keyring: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase from terminal: %v", err)
+	}
+
+	return passphrase, nil
+}
+
+// fileKeyring stores items as a JSON map of item key to JWE compact-serialized token, similar to
+// aws-vault's file backend. Each token is wrapped with PBES2-HS256+A128KW and its content is
+// encrypted with A256GCM.
+type fileKeyring struct {
+	dir            string
+	passphraseFunc PassphraseFunc
+}
+
+func newFileKeyring(cfg Config) (*fileKeyring, error) {
+	dir := cfg.FileDir
+	if dir == "" {
+		dir = DefaultFileDir
+	}
+
+	dir, err := expandHomeDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create keyring directory '%s': %v", dir, err)
+	}
+
+	passphraseFunc := cfg.FilePassphraseFunc
+	if passphraseFunc == nil {
+		passphraseFunc = DefaultPassphraseFunc
+	}
+
+	return &fileKeyring{
+		dir:            dir,
+		passphraseFunc: passphraseFunc,
+	}, nil
+}
+
+func (k *fileKeyring) storePath() string {
+	return filepath.Join(k.dir, fileStoreName)
+}
+
+func (k *fileKeyring) load() (map[string]string, error) {
+	items := map[string]string{}
+
+	data, err := os.ReadFile(k.storePath())
+	if errors.Is(err, os.ErrNotExist) {
+		return items, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read file keyring '%s': %v", k.storePath(), err)
+	}
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("failed to parse file keyring '%s': %v", k.storePath(), err)
+	}
+
+	return items, nil
+}
+
+func (k *fileKeyring) save(items map[string]string) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(k.storePath(), data, 0o600)
+}
+
+func (k *fileKeyring) set(key string, data []byte) error {
+	passphrase, err := k.passphraseFunc()
+	if err != nil {
+		return err
+	}
+
+	encrypter, err := jose.NewEncrypter(
+		jose.A256GCM,
+		jose.Recipient{Algorithm: jose.PBES2_HS256_A128KW, Key: passphrase},
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create JWE encrypter: %v", err)
+	}
+
+	object, err := encrypter.Encrypt(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt item '%s': %v", key, err)
+	}
+
+	token, err := object.CompactSerialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize encrypted item '%s': %v", key, err)
+	}
+
+	items, err := k.load()
+	if err != nil {
+		return err
+	}
+
+	items[key] = token
+
+	return k.save(items)
+}
+
+func (k *fileKeyring) get(key string) ([]byte, error) {
+	items, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := items[key]
+	if !ok {
+		return nil, keyring.ErrKeyNotFound
+	}
+
+	passphrase, err := k.passphraseFunc()
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := jose.ParseEncrypted(
+		token,
+		[]jose.KeyAlgorithm{jose.PBES2_HS256_A128KW},
+		[]jose.ContentEncryption{jose.A256GCM},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse encrypted item '%s': %v", key, err)
+	}
+
+	data, err := object.Decrypt(passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt item '%s', check the passphrase: %v", key, err)
+	}
+
+	return data, nil
+}
+
+func (k *fileKeyring) remove(key string) error {
+	items, err := k.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := items[key]; !ok {
+		return nil
+	}
+
+	delete(items, key)
+
+	return k.save(items)
+}
+
+// keys returns every item key currently stored in the file keyring.
+func (k *fileKeyring) keys() ([]string, error) {
+	items, err := k.load()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func expandHomeDir(dir string) (string, error) {
+	if !strings.HasPrefix(dir, "~") {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %v", err)
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(dir, "~")), nil
+}
@@ -0,0 +1,124 @@
+package securestore
+
+import (
+	"testing"
+
+	"github.com/99designs/keyring"
+)
+
+func fixedPassphrase(passphrase string) PassphraseFunc {
+	return func() ([]byte, error) {
+		return []byte(passphrase), nil
+	}
+}
+
+func newTestFileKeyring(t *testing.T, passphrase string) *fileKeyring {
+	t.Helper()
+
+	ring, err := newFileKeyring(Config{
+		FileDir:            t.TempDir(),
+		FilePassphraseFunc: fixedPassphrase(passphrase),
+	})
+	if err != nil {
+		t.Fatalf("newFileKeyring failed: %v", err)
+	}
+
+	return ring
+}
+
+func TestFileKeyringSetGetRoundTrip(t *testing.T) {
+	ring := newTestFileKeyring(t, "correct horse battery staple")
+
+	if err := ring.set("k", []byte("secret data")); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	got, err := ring.get("k")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+
+	if string(got) != "secret data" {
+		t.Fatalf("got %q, want %q", got, "secret data")
+	}
+}
+
+func TestFileKeyringGetWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+
+	writeRing, err := newFileKeyring(Config{FileDir: dir, FilePassphraseFunc: fixedPassphrase("correct")})
+	if err != nil {
+		t.Fatalf("newFileKeyring failed: %v", err)
+	}
+	if err := writeRing.set("k", []byte("secret data")); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	readRing, err := newFileKeyring(Config{FileDir: dir, FilePassphraseFunc: fixedPassphrase("wrong")})
+	if err != nil {
+		t.Fatalf("newFileKeyring failed: %v", err)
+	}
+
+	if _, err := readRing.get("k"); err == nil {
+		t.Fatal("got nil error, want a decryption failure with the wrong passphrase")
+	}
+}
+
+func TestFileKeyringGetMissingKey(t *testing.T) {
+	ring := newTestFileKeyring(t, "correct horse battery staple")
+
+	if _, err := ring.get("missing"); err != keyring.ErrKeyNotFound {
+		t.Fatalf("got err %v, want keyring.ErrKeyNotFound", err)
+	}
+}
+
+func TestFileKeyringRemoveThenList(t *testing.T) {
+	ring := newTestFileKeyring(t, "correct horse battery staple")
+
+	if err := ring.set("a", []byte("one")); err != nil {
+		t.Fatalf("set a failed: %v", err)
+	}
+	if err := ring.set("b", []byte("two")); err != nil {
+		t.Fatalf("set b failed: %v", err)
+	}
+
+	if err := ring.remove("a"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+
+	keys, err := ring.keys()
+	if err != nil {
+		t.Fatalf("keys failed: %v", err)
+	}
+
+	if len(keys) != 1 || keys[0] != "b" {
+		t.Fatalf("got keys %v, want [b]", keys)
+	}
+
+	if _, err := ring.get("a"); err != keyring.ErrKeyNotFound {
+		t.Fatalf("got err %v, want keyring.ErrKeyNotFound after remove", err)
+	}
+}
+
+func TestExpandHomeDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	got, err := expandHomeDir("~/ocm/keyring")
+	if err != nil {
+		t.Fatalf("expandHomeDir failed: %v", err)
+	}
+
+	want := home + "/ocm/keyring"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	got, err = expandHomeDir("/absolute/path")
+	if err != nil {
+		t.Fatalf("expandHomeDir failed: %v", err)
+	}
+	if got != "/absolute/path" {
+		t.Fatalf("got %q, want unchanged absolute path", got)
+	}
+}
@@ -25,7 +25,20 @@ var (
 		string(keyring.KeychainBackend),
 		string(keyring.SecretServiceBackend),
 		string(keyring.PassBackend),
+		string(keyring.KeyCtlBackend),
+		FileBackend,
+		OnePasswordBackend,
 	}
+
+	// KeyCtlScope selects the kernel keyring that the KeyCtlBackend stores items in. It must be
+	// one of "user", "session", "process" or "thread". It defaults to "user" so that items
+	// survive across the processes of a single user, which matches how the other backends behave.
+	KeyCtlScope = "user"
+
+	// KeyCtlPerm is the permission mask applied to items created in the KeyCtlBackend. It
+	// defaults to 0x3f3f0000, which grants the possessor and the owning user read/write access
+	// and nothing to anyone else.
+	KeyCtlPerm uint32 = 0x3f3f0000
 )
 
 func getKeyringConfig(backend string) keyring.Config {
@@ -42,6 +55,9 @@ func getKeyringConfig(backend string) keyring.Config {
 		WinCredPrefix: ItemKey,
 		// Secret Service
 		LibSecretCollectionName: CollectionName,
+		// Linux kernel keyring
+		KeyCtlScope: KeyCtlScope,
+		KeyCtlPerm:  KeyCtlPerm,
 	}
 }
 
@@ -67,7 +83,15 @@ func IsBackendAvailable(backend string) (isAvailable bool) {
 //
 // Note: CGO_ENABLED=1 is required for darwin builds (enables OSX Keychain)
 func AvailableBackends() []string {
-	b := []string{}
+	// The file backend is pure Go and never depends on an OS keyring service, so it is always
+	// available.
+	b := []string{FileBackend}
+
+	// The 1Password backend shells out to the `op` CLI, so it's only available when the binary
+	// is installed and signed in to a session.
+	if isOPAvailable() {
+		b = append(b, OnePasswordBackend)
+	}
 
 	// Intersection between available backends from OS and allowed backends
 	for _, avail := range keyring.AvailableBackends() {
@@ -81,104 +105,34 @@ func AvailableBackends() []string {
 	return b
 }
 
-// UpsertConfigToKeyring will upsert the provided credentials to the desired OS secure store.
+// UpsertConfigToKeyring will upsert the provided credentials to the desired OS secure store,
+// under DefaultProfile. An optional Config may be passed to configure backend-specific settings,
+// such as the file backend's store directory and passphrase source. It is a thin wrapper around
+// UpsertConfigToKeyringForProfile for callers that don't need multiple profiles.
 //
 // Note: CGO_ENABLED=1 is required for darwin builds (enables OSX Keychain)
-func UpsertConfigToKeyring(backend string, creds []byte) error {
-	if err := ValidateBackend(backend); err != nil {
-		return err
-	}
-
-	ring, err := keyring.Open(getKeyringConfig(backend))
-	if err != nil {
-		return err
-	}
-
-	compressed, err := compressConfig(creds)
-	if err != nil {
-		return err
-	}
-
-	// check if available backend contains windows credential manager and exceeds the byte limit
-	if len(compressed) > MaxWindowsByteSize &&
-		backend == string(keyring.WinCredBackend) {
-		return fmt.Errorf("credentials are too large for Windows Credential Manager: %d bytes (max %d)", len(compressed), MaxWindowsByteSize)
-	}
-
-	err = ring.Set(keyring.Item{
-		Label:       ItemKey,
-		Key:         ItemKey,
-		Description: KindInternetPassword,
-		Data:        compressed,
-	})
-
-	return err
+func UpsertConfigToKeyring(backend string, creds []byte, cfg ...Config) error {
+	return UpsertConfigToKeyringForProfile(backend, DefaultProfile, creds, cfg...)
 }
 
-// RemoveConfigFromKeyring will remove the credentials from the first priority OS secure store.
+// RemoveConfigFromKeyring will remove the credentials from the first priority OS secure store,
+// under DefaultProfile. An optional Config may be passed to configure backend-specific settings,
+// such as the file backend's store directory and passphrase source. It is a thin wrapper around
+// RemoveConfigFromKeyringForProfile for callers that don't need multiple profiles.
 //
 // Note: CGO_ENABLED=1 is required for OSX Keychain and darwin builds
-func RemoveConfigFromKeyring(backend string) error {
-	if err := ValidateBackend(backend); err != nil {
-		return err
-	}
-
-	ring, err := keyring.Open(getKeyringConfig(backend))
-	if err != nil {
-		return err
-	}
-
-	err = ring.Remove(ItemKey)
-	if err != nil {
-		if err == keyring.ErrKeyNotFound {
-			// Ignore not found errors, key is already removed
-			return nil
-		}
-
-		if strings.Contains(err.Error(), "Keychain Error. (-25244)") {
-			return fmt.Errorf("%s\nThis application may not have permission to delete from the Keychain. Please check the permissions in the Keychain and try again", err.Error())
-		}
-	}
-
-	return err
+func RemoveConfigFromKeyring(backend string, cfg ...Config) error {
+	return RemoveConfigFromKeyringForProfile(backend, DefaultProfile, cfg...)
 }
 
-// GetConfigFromKeyring will retrieve the credentials from the first priority OS secure store.
+// GetConfigFromKeyring will retrieve the credentials from the first priority OS secure store,
+// under DefaultProfile. An optional Config may be passed to configure backend-specific settings,
+// such as the file backend's store directory and passphrase source. It is a thin wrapper around
+// GetConfigFromKeyringForProfile for callers that don't need multiple profiles.
 //
 // Note: CGO_ENABLED=1 is required for darwin builds (enables OSX Keychain)
-func GetConfigFromKeyring(backend string) ([]byte, error) {
-	if err := ValidateBackend(backend); err != nil {
-		return nil, err
-	}
-
-	credentials := []byte("")
-
-	ring, err := keyring.Open(getKeyringConfig(backend))
-	if err != nil {
-		return nil, err
-	}
-
-	i, err := ring.Get(ItemKey)
-	if err != nil && err != keyring.ErrKeyNotFound {
-		return credentials, err
-	} else if err == keyring.ErrKeyNotFound {
-		// Not found, continue
-	} else {
-		credentials = i.Data
-	}
-
-	if len(credentials) == 0 {
-		// No creds to decompress, return early
-		return credentials, nil
-	}
-
-	creds, err := decompressConfig(credentials)
-	if err != nil {
-		return nil, err
-	}
-
-	return creds, nil
-
+func GetConfigFromKeyring(backend string, cfg ...Config) ([]byte, error) {
+	return GetConfigFromKeyringForProfile(backend, DefaultProfile, cfg...)
 }
 
 // Validates that the requested backend is valid and available, returns an error if not.
@@ -201,6 +155,10 @@ func ValidateBackend(backend string) error {
 	}
 
 	if !IsBackendAvailable(backend) {
+		if backend == OnePasswordBackend {
+			return fmt.Errorf("%w: the 1Password backend requires the `op` CLI to be installed and signed in, run `op signin` and try again", ErrKeyringUnavailable)
+		}
+
 		return ErrKeyringUnavailable
 	}
 
@@ -0,0 +1,272 @@
+package securestore
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/99designs/keyring"
+)
+
+// winCredChunkKeyPattern matches the `.partN` suffix winCredChunkKey appends to a manifest's
+// key, so ListProfiles can tell a WinCred chunk item apart from a profile whose name happens to
+// contain the substring "part".
+var winCredChunkKeyPattern = regexp.MustCompile(`\.part\d+$`)
+
+// DefaultProfile is the profile used by UpsertConfigToKeyring, GetConfigFromKeyring and
+// RemoveConfigFromKeyring, so a single-profile caller behaves exactly as before profiles existed.
+const DefaultProfile = "default"
+
+// profilePrefix is prepended to every profile other than DefaultProfile when deriving its item
+// key, mirroring how kubeconfig contexts and aws-vault profiles namespace their entries.
+const profilePrefix = ItemKey + "/"
+
+// profileKey returns the item key used to store a given profile's credentials. DefaultProfile
+// maps to the plain ItemKey so items written before profiles were introduced keep working.
+func profileKey(profile string) string {
+	if profile == "" || profile == DefaultProfile {
+		return ItemKey
+	}
+
+	return profilePrefix + profile
+}
+
+// UpsertConfigToKeyringForProfile will upsert the provided credentials to the desired OS secure
+// store under the given profile. An optional Config may be passed to configure backend-specific
+// settings, such as the file backend's store directory and passphrase source.
+//
+// Note: CGO_ENABLED=1 is required for darwin builds (enables OSX Keychain)
+func UpsertConfigToKeyringForProfile(backend string, profile string, creds []byte, cfg ...Config) error {
+	if err := ValidateBackend(backend); err != nil {
+		return err
+	}
+
+	key := profileKey(profile)
+
+	compressed, err := compressConfig(creds)
+	if err != nil {
+		return err
+	}
+
+	if backend == FileBackend {
+		ring, err := newFileKeyring(firstConfig(cfg...))
+		if err != nil {
+			return err
+		}
+
+		return wrapAccessError(ring.set(key, compressed))
+	}
+
+	if backend == OnePasswordBackend {
+		return wrapAccessError(newOPKeyring(firstConfig(cfg...)).Set(keyring.Item{
+			Label:       key,
+			Key:         key,
+			Description: KindInternetPassword,
+			Data:        compressed,
+		}))
+	}
+
+	ring, err := keyring.Open(getKeyringConfig(backend))
+	if err != nil {
+		return err
+	}
+
+	// Windows Credential Manager caps individual items at MaxWindowsByteSize, so payloads over
+	// that size are split into chunks plus a manifest instead of a single Set call.
+	if backend == string(keyring.WinCredBackend) && len(compressed) > MaxWindowsByteSize {
+		return wrapAccessError(winCredSetChunked(ring, key, compressed))
+	}
+
+	if backend == string(keyring.WinCredBackend) {
+		// The payload now fits in a single item; if a previous write chunked this key, its
+		// `.partN` items would otherwise be left behind.
+		if err := winCredClearStaleChunks(ring, key, 0); err != nil {
+			return wrapAccessError(err)
+		}
+	}
+
+	err = ring.Set(keyring.Item{
+		Label:       key,
+		Key:         key,
+		Description: KindInternetPassword,
+		Data:        compressed,
+	})
+
+	return wrapAccessError(err)
+}
+
+// RemoveConfigFromKeyringForProfile will remove the credentials for the given profile from the
+// first priority OS secure store. An optional Config may be passed to configure backend-specific
+// settings, such as the file backend's store directory and passphrase source.
+//
+// Note: CGO_ENABLED=1 is required for OSX Keychain and darwin builds
+func RemoveConfigFromKeyringForProfile(backend string, profile string, cfg ...Config) error {
+	if err := ValidateBackend(backend); err != nil {
+		return err
+	}
+
+	key := profileKey(profile)
+
+	if backend == FileBackend {
+		ring, err := newFileKeyring(firstConfig(cfg...))
+		if err != nil {
+			return err
+		}
+
+		return wrapAccessError(ring.remove(key))
+	}
+
+	if backend == OnePasswordBackend {
+		return wrapAccessError(newOPKeyring(firstConfig(cfg...)).Remove(key))
+	}
+
+	ring, err := keyring.Open(getKeyringConfig(backend))
+	if err != nil {
+		return err
+	}
+
+	if backend == string(keyring.WinCredBackend) {
+		return wrapAccessError(winCredRemoveChunked(ring, key))
+	}
+
+	err = ring.Remove(key)
+	if err != nil {
+		if err == keyring.ErrKeyNotFound {
+			// Ignore not found errors, key is already removed
+			return nil
+		}
+
+		return wrapAccessError(err)
+	}
+
+	return nil
+}
+
+// GetConfigFromKeyringForProfile will retrieve the credentials for the given profile from the
+// first priority OS secure store. An optional Config may be passed to configure backend-specific
+// settings, such as the file backend's store directory and passphrase source.
+//
+// Note: CGO_ENABLED=1 is required for darwin builds (enables OSX Keychain)
+func GetConfigFromKeyringForProfile(backend string, profile string, cfg ...Config) ([]byte, error) {
+	if err := ValidateBackend(backend); err != nil {
+		return nil, err
+	}
+
+	key := profileKey(profile)
+	credentials := []byte("")
+
+	if backend == FileBackend {
+		ring, err := newFileKeyring(firstConfig(cfg...))
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := ring.get(key)
+		if err != nil && err != keyring.ErrKeyNotFound {
+			return nil, wrapAccessError(err)
+		} else if err == nil {
+			credentials = data
+		}
+	} else if backend == OnePasswordBackend {
+		item, err := newOPKeyring(firstConfig(cfg...)).Get(key)
+		if err != nil && err != keyring.ErrKeyNotFound {
+			return nil, wrapAccessError(err)
+		} else if err == nil {
+			credentials = item.Data
+		}
+	} else {
+		ring, err := keyring.Open(getKeyringConfig(backend))
+		if err != nil {
+			return nil, err
+		}
+
+		if backend == string(keyring.WinCredBackend) {
+			data, err := winCredGetChunked(ring, key)
+			if err != nil && err != keyring.ErrKeyNotFound {
+				return nil, wrapAccessError(err)
+			} else if err == nil {
+				credentials = data
+			}
+		} else {
+			i, err := ring.Get(key)
+			if err != nil && err != keyring.ErrKeyNotFound {
+				return credentials, wrapAccessError(err)
+			} else if err == keyring.ErrKeyNotFound {
+				// Not found, continue
+			} else {
+				credentials = i.Data
+			}
+		}
+	}
+
+	if len(credentials) == 0 {
+		// No creds to decompress, return early
+		return credentials, nil
+	}
+
+	creds, err := decompressConfig(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	return creds, nil
+}
+
+// ListProfiles returns the names of every profile with credentials stored in the given backend,
+// including DefaultProfile if present. This lets tooling that juggles multiple OCM environments
+// (e.g. prod, stage, integration) discover what's already configured.
+func ListProfiles(backend string, cfg ...Config) ([]string, error) {
+	if err := ValidateBackend(backend); err != nil {
+		return nil, err
+	}
+
+	var itemKeys []string
+	var err error
+
+	switch backend {
+	case FileBackend:
+		ring, ferr := newFileKeyring(firstConfig(cfg...))
+		if ferr != nil {
+			return nil, ferr
+		}
+
+		itemKeys, err = ring.keys()
+	case OnePasswordBackend:
+		itemKeys, err = newOPKeyring(firstConfig(cfg...)).Keys()
+	default:
+		var ring keyring.Keyring
+		ring, err = keyring.Open(getKeyringConfig(backend))
+		if err == nil {
+			itemKeys, err = ring.Keys()
+		}
+	}
+	if err != nil {
+		return nil, wrapAccessError(err)
+	}
+
+	return filterProfileKeys(backend, itemKeys), nil
+}
+
+// filterProfileKeys turns the raw item keys stored in backend into profile names, aliasing
+// ItemKey back to DefaultProfile and skipping keys that aren't profiles at all. It is split out
+// from ListProfiles so the filtering can be tested without opening a real backend.
+func filterProfileKeys(backend string, itemKeys []string) []string {
+	profiles := []string{}
+	for _, key := range itemKeys {
+		// Skip the per-chunk items that the WinCred backend stores alongside a profile's
+		// manifest; they aren't profiles in their own right. Only WinCred ever creates these, and
+		// only a literal `.partN` suffix qualifies, so a profile named e.g. "us.partner" isn't
+		// mistaken for one.
+		if backend == string(keyring.WinCredBackend) && winCredChunkKeyPattern.MatchString(key) {
+			continue
+		}
+
+		switch {
+		case key == ItemKey:
+			profiles = append(profiles, DefaultProfile)
+		case strings.HasPrefix(key, profilePrefix):
+			profiles = append(profiles, strings.TrimPrefix(key, profilePrefix))
+		}
+	}
+
+	return profiles
+}
@@ -0,0 +1,97 @@
+package securestore
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/99designs/keyring"
+)
+
+func TestProfileKey(t *testing.T) {
+	cases := []struct {
+		profile string
+		want    string
+	}{
+		{"", ItemKey},
+		{DefaultProfile, ItemKey},
+		{"stage", profilePrefix + "stage"},
+		{"us.partner", profilePrefix + "us.partner"},
+	}
+
+	for _, c := range cases {
+		if got := profileKey(c.profile); got != c.want {
+			t.Errorf("profileKey(%q) = %q, want %q", c.profile, got, c.want)
+		}
+	}
+}
+
+func TestListProfilesFileBackend(t *testing.T) {
+	cfg := Config{FileDir: t.TempDir(), FilePassphraseFunc: fixedPassphrase("pw")}
+
+	for _, profile := range []string{DefaultProfile, "us.partner", "stage"} {
+		if err := UpsertConfigToKeyringForProfile(FileBackend, profile, []byte("creds"), cfg); err != nil {
+			t.Fatalf("profile %q: UpsertConfigToKeyringForProfile failed: %v", profile, err)
+		}
+	}
+
+	profiles, err := ListProfiles(FileBackend, cfg)
+	if err != nil {
+		t.Fatalf("ListProfiles failed: %v", err)
+	}
+
+	sort.Strings(profiles)
+	want := []string{DefaultProfile, "stage", "us.partner"}
+	sort.Strings(want)
+
+	if !reflect.DeepEqual(profiles, want) {
+		t.Fatalf("got profiles %v, want %v", profiles, want)
+	}
+}
+
+func TestFilterProfileKeysDefaultProfile(t *testing.T) {
+	got := filterProfileKeys(string(keyring.WinCredBackend), []string{ItemKey})
+
+	if !reflect.DeepEqual(got, []string{DefaultProfile}) {
+		t.Fatalf("got %v, want [%s]", got, DefaultProfile)
+	}
+}
+
+func TestFilterProfileKeysPartSubstringIsNotAChunk(t *testing.T) {
+	got := filterProfileKeys(string(keyring.WinCredBackend), []string{profilePrefix + "us.partner"})
+
+	if !reflect.DeepEqual(got, []string{"us.partner"}) {
+		t.Fatalf("got %v, want [us.partner]", got)
+	}
+}
+
+func TestFilterProfileKeysExcludesWinCredChunkSuffix(t *testing.T) {
+	itemKeys := []string{
+		ItemKey,
+		profilePrefix + "us.partner",
+		winCredChunkKey(profilePrefix+"stage", 3),
+	}
+
+	got := filterProfileKeys(string(keyring.WinCredBackend), itemKeys)
+	sort.Strings(got)
+
+	want := []string{DefaultProfile, "us.partner"}
+	sort.Strings(want)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got profiles %v, want %v (the .part3 chunk key must be excluded)", got, want)
+	}
+}
+
+func TestFilterProfileKeysChunkSuffixOnlySkippedForWinCred(t *testing.T) {
+	key := winCredChunkKey(profilePrefix+"stage", 3)
+
+	got := filterProfileKeys(FileBackend, []string{key})
+
+	// Non-WinCred backends never create `.partN` chunk items, so a profile whose name happens to
+	// look like one isn't special-cased away.
+	want := "stage.part3"
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("got %v, want [%s]", got, want)
+	}
+}
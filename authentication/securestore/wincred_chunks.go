@@ -0,0 +1,167 @@
+package securestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/99designs/keyring"
+)
+
+const (
+	// winCredChunkSize is the maximum number of bytes stored in each chunk item. It is kept
+	// comfortably under MaxWindowsByteSize to leave room for the per-item overhead that Windows
+	// Credential Manager adds on top of the raw data.
+	winCredChunkSize = 2000
+
+	winCredManifestVersion = 1
+)
+
+// winCredManifest describes how a payload larger than MaxWindowsByteSize was split across
+// multiple Windows Credential Manager items, so it can be reassembled and verified on read.
+type winCredManifest struct {
+	Version    byte   `json:"version"`
+	Size       int    `json:"size"`
+	SHA256     string `json:"sha256"`
+	ChunkCount int    `json:"chunk_count"`
+}
+
+func winCredChunkKey(key string, i int) string {
+	return fmt.Sprintf("%s.part%d", key, i)
+}
+
+// winCredSetChunked splits data across `<key>.partN` items and writes a `<key>` manifest item
+// describing how to reassemble and verify them. It is used in place of a single Set call
+// whenever the compressed payload exceeds MaxWindowsByteSize.
+func winCredSetChunked(ring keyring.Keyring, key string, data []byte) error {
+	sum := sha256.Sum256(data)
+
+	chunkCount := 0
+	for offset := 0; offset < len(data); offset += winCredChunkSize {
+		end := offset + winCredChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		chunkKey := winCredChunkKey(key, chunkCount)
+		if err := ring.Set(keyring.Item{
+			Label:       chunkKey,
+			Key:         chunkKey,
+			Description: KindInternetPassword,
+			Data:        data[offset:end],
+		}); err != nil {
+			return fmt.Errorf("failed to store credentials chunk %d: %v", chunkCount, err)
+		}
+
+		chunkCount++
+	}
+
+	// A previous write may have split the same key into more chunks than this one needs (e.g.
+	// the credentials shrank); anything beyond chunkCount is now orphaned and must be removed so
+	// it doesn't linger in the store forever.
+	if err := winCredClearStaleChunks(ring, key, chunkCount); err != nil {
+		return err
+	}
+
+	manifest, err := json.Marshal(winCredManifest{
+		Version:    winCredManifestVersion,
+		Size:       len(data),
+		SHA256:     hex.EncodeToString(sum[:]),
+		ChunkCount: chunkCount,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ring.Set(keyring.Item{
+		Label:       key,
+		Key:         key,
+		Description: KindInternetPassword,
+		Data:        manifest,
+	})
+}
+
+// winCredGetChunked reads the `key` item and, if it holds a winCredManifest, fetches and
+// concatenates its chunks and verifies their checksum. Items written before chunking was
+// introduced hold the plain payload directly, so those are returned unchanged.
+func winCredGetChunked(ring keyring.Keyring, key string) ([]byte, error) {
+	item, err := ring.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest winCredManifest
+	if err := json.Unmarshal(item.Data, &manifest); err != nil {
+		// Not a manifest: this is a pre-chunking item, return its payload as-is.
+		return item.Data, nil
+	}
+
+	data := make([]byte, 0, manifest.Size)
+	for i := 0; i < manifest.ChunkCount; i++ {
+		chunk, err := ring.Get(winCredChunkKey(key, i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read credentials chunk %d: %v", i, err)
+		}
+
+		data = append(data, chunk.Data...)
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != manifest.SHA256 {
+		return nil, fmt.Errorf("chunked credentials failed checksum verification")
+	}
+
+	return data, nil
+}
+
+// winCredClearStaleChunks removes any `<key>.partN` items left over from a previous chunked
+// write of key that a new write of keepCount chunks no longer needs. It reads the existing
+// manifest for key and is a no-op if the key doesn't exist yet or wasn't previously chunked.
+func winCredClearStaleChunks(ring keyring.Keyring, key string, keepCount int) error {
+	item, err := ring.Get(key)
+	if err != nil {
+		if err == keyring.ErrKeyNotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	var manifest winCredManifest
+	if err := json.Unmarshal(item.Data, &manifest); err != nil {
+		// Not a manifest: nothing chunked to clean up.
+		return nil
+	}
+
+	for i := keepCount; i < manifest.ChunkCount; i++ {
+		if err := ring.Remove(winCredChunkKey(key, i)); err != nil && err != keyring.ErrKeyNotFound {
+			return fmt.Errorf("failed to remove stale credentials chunk %d: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// winCredRemoveChunked removes the `key` manifest item and all of its chunks, if any.
+func winCredRemoveChunked(ring keyring.Keyring, key string) error {
+	item, err := ring.Get(key)
+	if err != nil {
+		if err == keyring.ErrKeyNotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	var manifest winCredManifest
+	if err := json.Unmarshal(item.Data, &manifest); err == nil {
+		for i := 0; i < manifest.ChunkCount; i++ {
+			if err := ring.Remove(winCredChunkKey(key, i)); err != nil && err != keyring.ErrKeyNotFound {
+				return fmt.Errorf("failed to remove credentials chunk %d: %v", i, err)
+			}
+		}
+	}
+
+	return ring.Remove(key)
+}
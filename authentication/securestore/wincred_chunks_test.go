@@ -0,0 +1,238 @@
+package securestore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/99designs/keyring"
+)
+
+// memKeyring is a minimal in-memory keyring.Keyring used to exercise the WinCred chunking logic
+// without depending on an actual Windows Credential Manager.
+type memKeyring struct {
+	items map[string]keyring.Item
+}
+
+func newMemKeyring() *memKeyring {
+	return &memKeyring{items: map[string]keyring.Item{}}
+}
+
+func (m *memKeyring) Get(key string) (keyring.Item, error) {
+	item, ok := m.items[key]
+	if !ok {
+		return keyring.Item{}, keyring.ErrKeyNotFound
+	}
+
+	return item, nil
+}
+
+func (m *memKeyring) Set(item keyring.Item) error {
+	m.items[item.Key] = item
+	return nil
+}
+
+func (m *memKeyring) Remove(key string) error {
+	if _, ok := m.items[key]; !ok {
+		return keyring.ErrKeyNotFound
+	}
+
+	delete(m.items, key)
+	return nil
+}
+
+func (m *memKeyring) Keys() ([]string, error) {
+	keys := make([]string, 0, len(m.items))
+	for key := range m.items {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func TestWinCredSetGetChunkedRoundTrip(t *testing.T) {
+	for _, size := range []int{1, winCredChunkSize - 1, winCredChunkSize, winCredChunkSize + 1, winCredChunkSize*3 + 7} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i % 251)
+		}
+
+		ring := newMemKeyring()
+		if err := winCredSetChunked(ring, "k", data); err != nil {
+			t.Fatalf("size %d: winCredSetChunked failed: %v", size, err)
+		}
+
+		got, err := winCredGetChunked(ring, "k")
+		if err != nil {
+			t.Fatalf("size %d: winCredGetChunked failed: %v", size, err)
+		}
+
+		if string(got) != string(data) {
+			t.Fatalf("size %d: round trip mismatch: got %d bytes, want %d", size, len(got), len(data))
+		}
+	}
+}
+
+func TestWinCredGetChunkedLegacyPlainItem(t *testing.T) {
+	ring := newMemKeyring()
+	if err := ring.Set(keyring.Item{Key: "k", Data: []byte("pre-chunking payload")}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	got, err := winCredGetChunked(ring, "k")
+	if err != nil {
+		t.Fatalf("winCredGetChunked failed: %v", err)
+	}
+
+	if string(got) != "pre-chunking payload" {
+		t.Fatalf("got %q, want %q", got, "pre-chunking payload")
+	}
+}
+
+func TestWinCredGetChunkedChecksumMismatch(t *testing.T) {
+	ring := newMemKeyring()
+	data := make([]byte, winCredChunkSize+10)
+	if err := winCredSetChunked(ring, "k", data); err != nil {
+		t.Fatalf("winCredSetChunked failed: %v", err)
+	}
+
+	corrupted, err := ring.Get(winCredChunkKey("k", 0))
+	if err != nil {
+		t.Fatalf("Get chunk 0 failed: %v", err)
+	}
+	corrupted.Data = append([]byte{corrupted.Data[0] ^ 0xff}, corrupted.Data[1:]...)
+	if err := ring.Set(corrupted); err != nil {
+		t.Fatalf("Set corrupted chunk failed: %v", err)
+	}
+
+	if _, err := winCredGetChunked(ring, "k"); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("got err %v, want a checksum verification error", err)
+	}
+}
+
+func TestWinCredGetChunkedMissingChunk(t *testing.T) {
+	ring := newMemKeyring()
+	data := make([]byte, winCredChunkSize+10)
+	if err := winCredSetChunked(ring, "k", data); err != nil {
+		t.Fatalf("winCredSetChunked failed: %v", err)
+	}
+
+	if err := ring.Remove(winCredChunkKey("k", 1)); err != nil {
+		t.Fatalf("Remove chunk 1 failed: %v", err)
+	}
+
+	if _, err := winCredGetChunked(ring, "k"); err == nil {
+		t.Fatal("got nil error, want a failure reading the missing chunk")
+	}
+}
+
+func TestWinCredSetChunkedShrinkRemovesStaleChunks(t *testing.T) {
+	ring := newMemKeyring()
+	big := make([]byte, winCredChunkSize*4)
+	if err := winCredSetChunked(ring, "k", big); err != nil {
+		t.Fatalf("initial winCredSetChunked failed: %v", err)
+	}
+
+	small := make([]byte, winCredChunkSize+1)
+	if err := winCredSetChunked(ring, "k", small); err != nil {
+		t.Fatalf("shrinking winCredSetChunked failed: %v", err)
+	}
+
+	for i := 2; i < 4; i++ {
+		if _, err := ring.Get(winCredChunkKey("k", i)); err != keyring.ErrKeyNotFound {
+			t.Fatalf("chunk %d: got err %v, want keyring.ErrKeyNotFound", i, err)
+		}
+	}
+
+	got, err := winCredGetChunked(ring, "k")
+	if err != nil {
+		t.Fatalf("winCredGetChunked failed: %v", err)
+	}
+	if string(got) != string(small) {
+		t.Fatal("round trip after shrink returned the wrong payload")
+	}
+}
+
+func TestWinCredSetChunkedGrowWritesAllChunks(t *testing.T) {
+	ring := newMemKeyring()
+	small := make([]byte, winCredChunkSize-1)
+	if err := winCredSetChunked(ring, "k", small); err != nil {
+		t.Fatalf("initial winCredSetChunked failed: %v", err)
+	}
+
+	big := make([]byte, winCredChunkSize*3+1)
+	for i := range big {
+		big[i] = byte(i % 251)
+	}
+	if err := winCredSetChunked(ring, "k", big); err != nil {
+		t.Fatalf("growing winCredSetChunked failed: %v", err)
+	}
+
+	got, err := winCredGetChunked(ring, "k")
+	if err != nil {
+		t.Fatalf("winCredGetChunked failed: %v", err)
+	}
+	if string(got) != string(big) {
+		t.Fatal("round trip after grow returned the wrong payload")
+	}
+}
+
+func TestWinCredClearStaleChunksNoManifestIsNoop(t *testing.T) {
+	ring := newMemKeyring()
+	if err := winCredClearStaleChunks(ring, "missing", 0); err != nil {
+		t.Fatalf("got err %v, want nil for a key that doesn't exist", err)
+	}
+
+	if err := ring.Set(keyring.Item{Key: "k", Data: []byte("plain")}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := winCredClearStaleChunks(ring, "k", 0); err != nil {
+		t.Fatalf("got err %v, want nil for a non-manifest item", err)
+	}
+}
+
+func TestWinCredRemoveChunkedRemovesManifestAndChunks(t *testing.T) {
+	ring := newMemKeyring()
+	data := make([]byte, winCredChunkSize*2+1)
+	if err := winCredSetChunked(ring, "k", data); err != nil {
+		t.Fatalf("winCredSetChunked failed: %v", err)
+	}
+
+	if err := winCredRemoveChunked(ring, "k"); err != nil {
+		t.Fatalf("winCredRemoveChunked failed: %v", err)
+	}
+
+	keys, err := ring.Keys()
+	if err != nil {
+		t.Fatalf("Keys failed: %v", err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("got leftover keys %v, want none", keys)
+	}
+}
+
+func TestWinCredManifestMarshalUnmarshal(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello"))
+	manifest := winCredManifest{
+		Version:    winCredManifestVersion,
+		Size:       5,
+		SHA256:     hex.EncodeToString(sum[:]),
+		ChunkCount: 1,
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var got winCredManifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if got != manifest {
+		t.Fatalf("got %+v, want %+v", got, manifest)
+	}
+}